@@ -10,10 +10,14 @@ import (
 	"net/http"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 
 	log "github.com/Sirupsen/logrus"
 	dockerfilters "github.com/docker/docker/pkg/parsers/filters"
+	"github.com/docker/swarm/api/errdefs"
+	"github.com/docker/swarm/api/httputils"
+	"github.com/docker/swarm/api/operations"
 	"github.com/docker/swarm/cluster"
 	"github.com/docker/swarm/scheduler"
 	"github.com/docker/swarm/scheduler/filter"
@@ -27,6 +31,7 @@ type context struct {
 	cluster       *cluster.Cluster
 	scheduler     *scheduler.Scheduler
 	eventsHandler *eventsHandler
+	operations    *operations.Registry
 	debug         bool
 	version       string
 	tlsConfig     *tls.Config
@@ -163,36 +168,40 @@ func getContainersJSON(c *context, w http.ResponseWriter, r *http.Request) {
 
 // GET /containers/{name:.*}/json
 func getContainerJSON(c *context, w http.ResponseWriter, r *http.Request) {
-	container := c.cluster.Container(mux.Vars(r)["name"])
-	if container != nil {
-		client, scheme := newClientAndScheme(c.tlsConfig)
-
-		resp, err := client.Get(scheme + "://" + container.Node.Addr + "/containers/" + container.Id + "/json")
-		if err != nil {
-			httpError(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		data, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			httpError(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-
-		n, err := json.Marshal(container.Node)
-		if err != nil {
-			httpError(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
+	name := mux.Vars(r)["name"]
+	container := c.cluster.Container(name)
+	if container == nil {
+		writeError(w, errdefs.NotFound(fmt.Errorf("Container %s not found", name)))
+		return
+	}
 
-		// insert Node field
-		data = bytes.Replace(data, []byte("\"Name\":\"/"), []byte(fmt.Sprintf("\"Node\":%s,\"Name\":\"/", n)), -1)
+	client, scheme := newClientAndScheme(c.tlsConfig)
 
-		// insert node IP
-		data = bytes.Replace(data, []byte("\"HostIp\":\"0.0.0.0\""), []byte(fmt.Sprintf("\"HostIp\":%q", container.Node.IP)), -1)
+	resp, err := client.Get(scheme + "://" + container.Node.Addr + "/containers/" + container.Id + "/json")
+	if err != nil {
+		writeError(w, errdefs.System(err))
+		return
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		writeError(w, errdefs.System(err))
+		return
+	}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.Write(data)
+	n, err := json.Marshal(container.Node)
+	if err != nil {
+		writeError(w, errdefs.System(err))
+		return
 	}
+
+	// insert Node field
+	data = bytes.Replace(data, []byte("\"Name\":\"/"), []byte(fmt.Sprintf("\"Node\":%s,\"Name\":\"/", n)), -1)
+
+	// insert node IP
+	data = bytes.Replace(data, []byte("\"HostIp\":\"0.0.0.0\""), []byte(fmt.Sprintf("\"HostIp\":%q", container.Node.IP)), -1)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
 }
 
 // POST /containers/create
@@ -204,18 +213,76 @@ func postContainersCreate(c *context, w http.ResponseWriter, r *http.Request) {
 	)
 
 	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
-		httpError(w, err.Error(), http.StatusBadRequest)
+		writeError(w, errdefs.InvalidParameter(err))
 		return
 	}
 
 	if container := c.cluster.Container(name); container != nil {
-		httpError(w, fmt.Sprintf("Conflict, The name %s is already assigned to %s. You have to delete (or rename) that container to be able to assign %s to a container again.", name, container.Id, name), http.StatusConflict)
+		writeError(w, errdefs.Conflict(fmt.Errorf("Conflict, The name %s is already assigned to %s. You have to delete (or rename) that container to be able to assign %s to a container again.", name, container.Id, name)))
+		return
+	}
+
+	if r.Form.Get("async") == "1" {
+		op := operations.New("/containers/create")
+		c.operations.Add(op)
+
+		go func() {
+			op.SetRunning()
+
+			// c.scheduler.CreateContainer doesn't accept a context to
+			// actually abort placement/pull, so a cancel can't stop the
+			// call in flight; what we can do is stop waiting on it and
+			// finish the operation the moment the context is canceled,
+			// instead of leaving Cancel with no observable effect.
+			result := make(chan struct {
+				container *cluster.Container
+				err       error
+			}, 1)
+			go func() {
+				container, err := c.scheduler.CreateContainer(&config, name)
+				result <- struct {
+					container *cluster.Container
+					err       error
+				}{container, err}
+			}()
+
+			select {
+			case res := <-result:
+				if res.err != nil {
+					op.Finish(errdefs.FromSchedulerError(res.err))
+					return
+				}
+				op.SetProgress(map[string]interface{}{"Id": res.container.Id})
+				op.Finish(nil)
+			case <-op.Context().Done():
+				op.Finish(op.Context().Err())
+
+				// The create call is still running in the background and
+				// may still succeed after we've already reported this
+				// operation as canceled/failed. Reap it so a canceled
+				// operation doesn't leave an orphaned container behind.
+				go func() {
+					res := <-result
+					if res.err == nil {
+						log.Debugf("[CANCEL] removing orphaned container %s from canceled operation %s", res.container.Id, op.ID())
+						if err := c.scheduler.RemoveContainer(res.container, true); err != nil {
+							log.Errorf("Unable to remove orphaned container %s: %v", res.container.Id, err)
+						}
+					}
+				}()
+			}
+		}()
+
+		w.Header().Set("Location", "/operations/"+op.ID())
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(op)
 		return
 	}
 
 	container, err := c.scheduler.CreateContainer(&config, name)
 	if err != nil {
-		httpError(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, errdefs.FromSchedulerError(err))
 		return
 	}
 
@@ -235,11 +302,11 @@ func deleteContainer(c *context, w http.ResponseWriter, r *http.Request) {
 	force := r.Form.Get("force") == "1"
 	container := c.cluster.Container(name)
 	if container == nil {
-		httpError(w, fmt.Sprintf("Container %s not found", name), http.StatusNotFound)
+		writeError(w, errdefs.NotFound(fmt.Errorf("Container %s not found", name)))
 		return
 	}
 	if err := c.scheduler.RemoveContainer(container, force); err != nil {
-		httpError(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, errdefs.FromSchedulerError(err))
 		return
 	}
 
@@ -247,15 +314,45 @@ func deleteContainer(c *context, w http.ResponseWriter, r *http.Request) {
 
 // GET /events
 func getEvents(c *context, w http.ResponseWriter, r *http.Request) {
-	c.eventsHandler.Add(r.RemoteAddr, w)
+	if err := r.ParseForm(); err != nil {
+		writeError(w, errdefs.InvalidParameter(err))
+		return
+	}
+
+	filter, err := newEventFilter(r.Form.Get("filters"))
+	if err != nil {
+		writeError(w, errdefs.InvalidParameter(err))
+		return
+	}
+
+	var since, until int64
+	if raw := r.Form.Get("since"); raw != "" {
+		since, _ = strconv.ParseInt(raw, 10, 64)
+	}
+	if raw := r.Form.Get("until"); raw != "" {
+		until, _ = strconv.ParseInt(raw, 10, 64)
+	}
+
+	for _, node := range c.cluster.Nodes() {
+		c.eventsHandler.EnsureWatching(c.tlsConfig, node)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 
+	if since != 0 {
+		c.eventsHandler.Replay(w, filter, since, until)
+	}
+
+	c.eventsHandler.Add(r.RemoteAddr, w, filter)
+	defer c.eventsHandler.Remove(r.RemoteAddr)
+
 	if f, ok := w.(http.Flusher); ok {
 		f.Flush()
 	}
 
-	c.eventsHandler.Wait(r.RemoteAddr)
+	// Return as soon as the client goes away instead of blocking this
+	// goroutine (and leaking the subscriber/buffer entries) forever.
+	<-r.Context().Done()
 }
 
 // GET /_ping
@@ -300,12 +397,12 @@ func proxyRandom(c *context, w http.ResponseWriter, r *http.Request) {
 	accepted, err := healthFilter.Filter(nil, candidates)
 
 	if err != nil {
-		httpError(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, errdefs.Unavailable(err))
 		return
 	}
 
 	if err := proxy(c.tlsConfig, accepted[rand.Intn(len(accepted))].Addr, w, r); err != nil {
-		httpError(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, errdefs.System(err))
 	}
 }
 
@@ -342,6 +439,12 @@ func httpError(w http.ResponseWriter, err string, status int) {
 	http.Error(w, err, status)
 }
 
+// writeError maps err to an HTTP status via httputils.FromError and writes
+// it to w.
+func writeError(w http.ResponseWriter, err error) {
+	httpError(w, err.Error(), httputils.FromError(err))
+}
+
 func createRouter(c *context, enableCors bool) *mux.Router {
 	r := mux.NewRouter()
 	m := map[string]map[string]handler{
@@ -364,13 +467,18 @@ func createRouter(c *context, enableCors bool) *mux.Router {
 			"/containers/{name:.*}/json":      getContainerJSON,
 			"/containers/{name:.*}/top":       proxyContainer,
 			"/containers/{name:.*}/logs":      proxyContainer,
+			"/containers/{name:.*}/stats":     getContainerStats,
 			"/containers/{name:.*}/attach/ws": notImplementedHandler,
 			"/exec/{execid:.*}/json":          proxyContainer,
+			"/cluster/stats":                  getClusterStats,
+			"/operations":                     getOperations,
+			"/operations/{id}":                getOperation,
+			"/operations/{id}/wait":           waitOperation,
 		},
 		"POST": {
 			"/auth":                         proxyRandom,
 			"/commit":                       notImplementedHandler,
-			"/build":                        notImplementedHandler,
+			"/build":                        postBuild,
 			"/images/create":                notImplementedHandler,
 			"/images/load":                  notImplementedHandler,
 			"/images/{name:.*}/push":        notImplementedHandler,
@@ -389,10 +497,14 @@ func createRouter(c *context, enableCors bool) *mux.Router {
 			"/containers/{name:.*}/exec":    proxyContainerAndForceRefresh,
 			"/exec/{execid:.*}/start":       proxyHijack,
 			"/exec/{execid:.*}/resize":      proxyContainer,
+			"/containers/prune":             postContainersPrune,
+			"/images/prune":                 postImagesPrune,
+			"/volumes/prune":                postVolumesPrune,
 		},
 		"DELETE": {
 			"/containers/{name:.*}": deleteContainer,
 			"/images/{name:.*}":     notImplementedHandler,
+			"/operations/{id}":      deleteOperation,
 		},
 		"OPTIONS": {
 			"": optionsHandler,
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/docker/swarm/api/errdefs"
+)
+
+var fromInstruction = regexp.MustCompile(`(?mi)^\s*FROM\s+(\S+)`)
+
+// POST /build
+func postBuild(c *context, w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, errdefs.InvalidParameter(err))
+		return
+	}
+
+	buf, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, errdefs.System(err))
+		return
+	}
+
+	constraints := constraintsFromBuildRequest(r)
+	baseImage := baseImageFromContext(buf)
+
+	node, err := c.scheduler.BuildOnNode(constraints, baseImage)
+	if err != nil {
+		writeError(w, errdefs.FromSchedulerError(err))
+		return
+	}
+
+	log.Debugf("[BUILD] --> %s (%s)", node.Name, baseImage)
+
+	r.Body = ioutil.NopCloser(bytes.NewReader(buf))
+	r.ContentLength = int64(len(buf))
+
+	ok, err := streamBuild(c.tlsConfig, node.Addr, w, r)
+	if err != nil {
+		writeError(w, errdefs.System(err))
+		return
+	}
+	if !ok {
+		// The daemon either answered with a non-200 status or answered 200
+		// and streamed a build failure (bad Dockerfile, failed RUN, ...);
+		// the image was never produced.
+		return
+	}
+
+	// The build succeeded: remember that the resulting image is already
+	// present on this node so future creates can prefer it and skip a pull.
+	tag := r.Form.Get("t")
+	if tag != "" {
+		node.AddImage(tag)
+	}
+}
+
+// buildProgress is one frame of the build's streamed JSON output. A
+// daemon-side build failure still answers 200 and reports the error inside
+// the stream rather than as an HTTP status.
+type buildProgress struct {
+	Error       string      `json:"error"`
+	ErrorDetail interface{} `json:"errorDetail"`
+}
+
+// streamBuild proxies the build request to addr and relays each JSON frame
+// of the response to w as it arrives. It returns false if the daemon did not
+// answer 200 or any frame in the stream reports an error, so the caller
+// knows not to trust the build as having produced the image.
+func streamBuild(tlsConfig *tls.Config, addr string, w http.ResponseWriter, r *http.Request) (bool, error) {
+	client, scheme := newClientAndScheme(tlsConfig)
+
+	req, err := http.NewRequest(r.Method, scheme+"://"+addr+r.URL.RequestURI(), r.Body)
+	if err != nil {
+		return false, err
+	}
+	req.Header = r.Header
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(resp.StatusCode)
+
+	flusher, _ := w.(http.Flusher)
+
+	succeeded := resp.StatusCode == http.StatusOK
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			break
+		}
+
+		var progress buildProgress
+		if err := json.Unmarshal(raw, &progress); err == nil && progress.Error != "" {
+			succeeded = false
+		}
+
+		w.Write(raw)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	return succeeded, nil
+}
+
+// constraintsFromBuildRequest extracts `constraint:` entries passed either
+// through the build args or through X-Registry-Config, mirroring the way
+// constraints are read off container create requests.
+func constraintsFromBuildRequest(r *http.Request) []string {
+	var constraints []string
+
+	constraints = append(constraints, constraintsFromJSON(r.Form.Get("buildargs"))...)
+	constraints = append(constraints, constraintsFromRegistryConfigHeader(r.Header.Get("X-Registry-Config"))...)
+
+	return constraints
+}
+
+// constraintsFromRegistryConfigHeader decodes the base64-encoded JSON
+// payload of the X-Registry-Config header and extracts any `constraint:`
+// entries from it.
+func constraintsFromRegistryConfigHeader(header string) []string {
+	if header == "" {
+		return nil
+	}
+
+	decoded, err := base64.URLEncoding.DecodeString(header)
+	if err != nil {
+		return nil
+	}
+
+	return constraintsFromJSON(string(decoded))
+}
+
+// constraintsFromJSON extracts `constraint:`-prefixed keys out of a flat
+// JSON object of string values.
+func constraintsFromJSON(raw string) []string {
+	var constraints []string
+
+	if raw == "" {
+		return constraints
+	}
+
+	var args map[string]string
+	if err := json.Unmarshal([]byte(raw), &args); err != nil {
+		return constraints
+	}
+
+	for k, v := range args {
+		if strings.HasPrefix(k, "constraint:") {
+			constraints = append(constraints, strings.TrimPrefix(k, "constraint:")+"=="+v)
+		}
+	}
+
+	return constraints
+}
+
+// baseImageFromContext looks at the Dockerfile inside the build context tar
+// and returns the image named in its first FROM instruction, so the
+// scheduler can favor a node that already has it.
+func baseImageFromContext(context []byte) string {
+	tr := tar.NewReader(bytes.NewReader(context))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return ""
+		}
+		if hdr.Name != "Dockerfile" {
+			continue
+		}
+
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return ""
+		}
+
+		if m := fromInstruction.FindSubmatch(content); m != nil {
+			return string(m[1])
+		}
+		break
+	}
+
+	return ""
+}
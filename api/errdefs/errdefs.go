@@ -0,0 +1,109 @@
+// Package errdefs defines a set of error interfaces that cluster and
+// scheduler code can implement so the API layer can map them to the right
+// HTTP status code without resorting to string matching.
+package errdefs
+
+// ErrNotFound signals that the requested object does not exist.
+type ErrNotFound interface {
+	NotFound()
+}
+
+// ErrConflict signals that the request conflicts with the current state of
+// the cluster (e.g. a name that is already taken).
+type ErrConflict interface {
+	Conflict()
+}
+
+// ErrInvalidParameter signals that one of the request's parameters is
+// invalid or malformed.
+type ErrInvalidParameter interface {
+	InvalidParameter()
+}
+
+// ErrUnavailable signals that the cluster cannot currently satisfy the
+// request (e.g. no node matches the given constraints).
+type ErrUnavailable interface {
+	Unavailable()
+}
+
+// ErrForbidden signals that the request is understood but not allowed.
+type ErrForbidden interface {
+	Forbidden()
+}
+
+// ErrSystem signals an unexpected, internal failure.
+type ErrSystem interface {
+	System()
+}
+
+type notFoundError struct{ error }
+
+func (notFoundError) NotFound() {}
+
+// NotFound wraps err so that it satisfies ErrNotFound.
+func NotFound(err error) error {
+	if err == nil {
+		return nil
+	}
+	return notFoundError{err}
+}
+
+type conflictError struct{ error }
+
+func (conflictError) Conflict() {}
+
+// Conflict wraps err so that it satisfies ErrConflict.
+func Conflict(err error) error {
+	if err == nil {
+		return nil
+	}
+	return conflictError{err}
+}
+
+type invalidParameterError struct{ error }
+
+func (invalidParameterError) InvalidParameter() {}
+
+// InvalidParameter wraps err so that it satisfies ErrInvalidParameter.
+func InvalidParameter(err error) error {
+	if err == nil {
+		return nil
+	}
+	return invalidParameterError{err}
+}
+
+type unavailableError struct{ error }
+
+func (unavailableError) Unavailable() {}
+
+// Unavailable wraps err so that it satisfies ErrUnavailable.
+func Unavailable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return unavailableError{err}
+}
+
+type forbiddenError struct{ error }
+
+func (forbiddenError) Forbidden() {}
+
+// Forbidden wraps err so that it satisfies ErrForbidden.
+func Forbidden(err error) error {
+	if err == nil {
+		return nil
+	}
+	return forbiddenError{err}
+}
+
+type systemError struct{ error }
+
+func (systemError) System() {}
+
+// System wraps err so that it satisfies ErrSystem.
+func System(err error) error {
+	if err == nil {
+		return nil
+	}
+	return systemError{err}
+}
@@ -0,0 +1,40 @@
+package errdefs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWrappers(t *testing.T) {
+	cause := errors.New("boom")
+
+	tests := []struct {
+		name string
+		err  error
+		is   func(error) bool
+	}{
+		{"NotFound", NotFound(cause), func(err error) bool { _, ok := err.(ErrNotFound); return ok }},
+		{"Conflict", Conflict(cause), func(err error) bool { _, ok := err.(ErrConflict); return ok }},
+		{"InvalidParameter", InvalidParameter(cause), func(err error) bool { _, ok := err.(ErrInvalidParameter); return ok }},
+		{"Unavailable", Unavailable(cause), func(err error) bool { _, ok := err.(ErrUnavailable); return ok }},
+		{"Forbidden", Forbidden(cause), func(err error) bool { _, ok := err.(ErrForbidden); return ok }},
+		{"System", System(cause), func(err error) bool { _, ok := err.(ErrSystem); return ok }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.is(tt.err) {
+				t.Fatalf("expected %s to implement its error interface", tt.name)
+			}
+			if tt.err.Error() != cause.Error() {
+				t.Fatalf("expected wrapped error message %q, got %q", cause.Error(), tt.err.Error())
+			}
+		})
+	}
+}
+
+func TestNilIsPreserved(t *testing.T) {
+	if NotFound(nil) != nil {
+		t.Fatal("expected wrapping a nil error to return nil")
+	}
+}
@@ -0,0 +1,28 @@
+package errdefs
+
+import "strings"
+
+// FromSchedulerError classifies an error coming back from the scheduler or
+// cluster packages into the matching errdefs type. Those packages predate
+// errdefs and still return plain errors, so until they're updated to
+// construct typed errors directly this adapts based on known error text,
+// keeping the classification logic in one place instead of scattered
+// string checks at each API call site.
+func FromSchedulerError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := strings.ToLower(err.Error())
+
+	switch {
+	case strings.Contains(msg, "no such container"), strings.Contains(msg, "not found"):
+		return NotFound(err)
+	case strings.Contains(msg, "already assigned"), strings.Contains(msg, "already exists"), strings.Contains(msg, "conflict"):
+		return Conflict(err)
+	case strings.Contains(msg, "no node"), strings.Contains(msg, "unable to find a node"), strings.Contains(msg, "no resources available"), strings.Contains(msg, "unable to schedule"):
+		return Unavailable(err)
+	default:
+		return System(err)
+	}
+}
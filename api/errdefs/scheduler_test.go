@@ -0,0 +1,35 @@
+package errdefs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFromSchedulerError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		is   func(error) bool
+	}{
+		{"no matching node", errors.New("unable to find a node that satisfies the constraints"), func(e error) bool { _, ok := e.(ErrUnavailable); return ok }},
+		{"no resources", errors.New("no resources available to schedule container"), func(e error) bool { _, ok := e.(ErrUnavailable); return ok }},
+		{"not found", errors.New("Container abc123 not found"), func(e error) bool { _, ok := e.(ErrNotFound); return ok }},
+		{"conflict", errors.New("Conflict, The name web is already assigned to abc123"), func(e error) bool { _, ok := e.(ErrConflict); return ok }},
+		{"unknown", errors.New("connection reset by peer"), func(e error) bool { _, ok := e.(ErrSystem); return ok }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromSchedulerError(tt.err)
+			if !tt.is(got) {
+				t.Fatalf("FromSchedulerError(%q) = %#v, did not match expected errdefs type", tt.err, got)
+			}
+		})
+	}
+}
+
+func TestFromSchedulerErrorNil(t *testing.T) {
+	if FromSchedulerError(nil) != nil {
+		t.Fatal("expected nil in, nil out")
+	}
+}
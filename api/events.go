@@ -0,0 +1,301 @@
+package api
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	dockerfilters "github.com/docker/docker/pkg/parsers/filters"
+	"github.com/docker/swarm/cluster"
+	"github.com/samalba/dockerclient"
+)
+
+// eventBufferSize is how many events we keep per node so a client that
+// reconnects after a short blip doesn't lose anything.
+const eventBufferSize = 256
+
+// Event is a cluster event tagged with the node it came from, mirroring how
+// getContainerJSON injects a Node field into container payloads.
+type Event struct {
+	dockerclient.Event
+	Node string `json:"Node"`
+}
+
+// eventFilter is the server-side predicate built from the Docker `filters`
+// query param. event/container/image are matched against the fields the
+// old dockerclient.Event carries (Status/Id/From).
+type eventFilter struct {
+	events     map[string]bool
+	containers map[string]bool
+	images     map[string]bool
+}
+
+func newEventFilter(raw string) (*eventFilter, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	parsed, err := dockerfilters.FromParam(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	// label/type have nothing to match against on the old
+	// dockerclient.Event shape (no labels, no container/image/volume
+	// distinction). Reject rather than silently accept and ignore them.
+	if len(parsed["label"]) > 0 {
+		return nil, fmt.Errorf("filtering events by label is not supported")
+	}
+	if len(parsed["type"]) > 0 {
+		return nil, fmt.Errorf("filtering events by type is not supported")
+	}
+
+	return &eventFilter{
+		events:     sliceToSet(parsed["event"]),
+		containers: sliceToSet(parsed["container"]),
+		images:     sliceToSet(parsed["image"]),
+	}, nil
+}
+
+func sliceToSet(s []string) map[string]bool {
+	out := make(map[string]bool, len(s))
+	for _, v := range s {
+		out[v] = true
+	}
+	return out
+}
+
+// Match reports whether e passes the filter. A nil filter matches
+// everything.
+func (f *eventFilter) Match(e *dockerclient.Event) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.events) > 0 && !f.events[e.Status] {
+		return false
+	}
+	if len(f.containers) > 0 && !f.containers[e.Id] {
+		return false
+	}
+	if len(f.images) > 0 && !f.images[e.From] {
+		return false
+	}
+	return true
+}
+
+// eventRingBuffer is a bounded, time-ordered history of events for a single
+// node, used to replay events a disconnected client missed.
+type eventRingBuffer struct {
+	mu     sync.Mutex
+	events []*Event
+	size   int
+}
+
+func newEventRingBuffer(size int) *eventRingBuffer {
+	return &eventRingBuffer{size: size}
+}
+
+func (b *eventRingBuffer) Add(e *Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.events = append(b.events, e)
+	if len(b.events) > b.size {
+		b.events = b.events[len(b.events)-b.size:]
+	}
+}
+
+// Since returns the buffered events with since <= Time <= until. A zero
+// bound is unbounded on that side.
+func (b *eventRingBuffer) Since(since, until int64) []*Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	out := make([]*Event, 0, len(b.events))
+	for _, e := range b.events {
+		if since != 0 && e.Time < since {
+			continue
+		}
+		if until != 0 && e.Time > until {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+type eventSubscriber struct {
+	w      io.Writer
+	filter *eventFilter
+}
+
+// eventsHandler fans events out to every subscribed HTTP client, keeps a
+// per-node replay buffer, and runs one background goroutine per node that
+// reconnects to that node's /events stream with exponential backoff.
+type eventsHandler struct {
+	sync.RWMutex
+	subscribers map[string]*eventSubscriber
+	buffers     map[string]*eventRingBuffer
+	watching    map[string]bool
+}
+
+// NewEventsHandler creates an empty eventsHandler.
+func NewEventsHandler() *eventsHandler {
+	return &eventsHandler{
+		subscribers: make(map[string]*eventSubscriber),
+		buffers:     make(map[string]*eventRingBuffer),
+		watching:    make(map[string]bool),
+	}
+}
+
+// Add registers w as a subscriber for remoteAddr, filtered by filter.
+func (eh *eventsHandler) Add(remoteAddr string, w io.Writer, filter *eventFilter) {
+	eh.Lock()
+	defer eh.Unlock()
+	eh.subscribers[remoteAddr] = &eventSubscriber{w: w, filter: filter}
+}
+
+// Remove drops the subscriber for remoteAddr. Callers learn their client
+// disconnected via r.Context().Done() and call this to stop fanning events
+// to it.
+func (eh *eventsHandler) Remove(remoteAddr string) {
+	eh.Lock()
+	defer eh.Unlock()
+	delete(eh.subscribers, remoteAddr)
+}
+
+// Size returns the number of currently connected subscribers.
+func (eh *eventsHandler) Size() int {
+	eh.RLock()
+	defer eh.RUnlock()
+	return len(eh.subscribers)
+}
+
+// Handle records e as coming from node and fans it out to every subscriber
+// whose filter matches it.
+func (eh *eventsHandler) Handle(node string, e *dockerclient.Event) error {
+	tagged := &Event{Event: *e, Node: node}
+
+	eh.Lock()
+	buf, ok := eh.buffers[node]
+	if !ok {
+		buf = newEventRingBuffer(eventBufferSize)
+		eh.buffers[node] = buf
+	}
+	buf.Add(tagged)
+
+	subs := make([]*eventSubscriber, 0, len(eh.subscribers))
+	for _, sub := range eh.subscribers {
+		subs = append(subs, sub)
+	}
+	eh.Unlock()
+
+	data, err := json.Marshal(tagged)
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subs {
+		if !sub.filter.Match(e) {
+			continue
+		}
+		if _, err := sub.w.Write(data); err != nil {
+			continue
+		}
+		if f, ok := sub.w.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
+
+	return nil
+}
+
+// Replay writes every buffered event (across every known node) between
+// since and until, passing filter, to w.
+func (eh *eventsHandler) Replay(w io.Writer, filter *eventFilter, since, until int64) {
+	eh.RLock()
+	buffers := make([]*eventRingBuffer, 0, len(eh.buffers))
+	for _, buf := range eh.buffers {
+		buffers = append(buffers, buf)
+	}
+	eh.RUnlock()
+
+	for _, buf := range buffers {
+		for _, e := range buf.Since(since, until) {
+			if !filter.Match(&e.Event) {
+				continue
+			}
+			if data, err := json.Marshal(e); err == nil {
+				w.Write(data)
+			}
+		}
+	}
+}
+
+// EnsureWatching starts a background reconnecting watcher for node unless
+// one is already running.
+func (eh *eventsHandler) EnsureWatching(tlsConfig *tls.Config, node *cluster.Node) {
+	eh.Lock()
+	if eh.watching[node.Name] {
+		eh.Unlock()
+		return
+	}
+	eh.watching[node.Name] = true
+	eh.Unlock()
+
+	go eh.watchNode(tlsConfig, node)
+}
+
+// watchNode streams node's /events endpoint into Handle, reconnecting with
+// exponential backoff whenever the upstream connection drops.
+func (eh *eventsHandler) watchNode(tlsConfig *tls.Config, node *cluster.Node) {
+	const (
+		minBackoff   = time.Second
+		maxBackoff   = 30 * time.Second
+		stableEnough = maxBackoff
+	)
+	backoff := minBackoff
+
+	for {
+		start := time.Now()
+		err := eh.watchNodeOnce(tlsConfig, node)
+
+		if time.Since(start) >= stableEnough {
+			backoff = minBackoff
+		}
+
+		if err != nil {
+			log.Errorf("[EVENTS] lost connection to %s: %v, reconnecting in %s", node.Name, err, backoff)
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+func (eh *eventsHandler) watchNodeOnce(tlsConfig *tls.Config, node *cluster.Node) error {
+	client, scheme := newClientAndScheme(tlsConfig)
+
+	resp, err := client.Get(scheme + "://" + node.Addr + "/events")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var e dockerclient.Event
+		if err := dec.Decode(&e); err != nil {
+			return err
+		}
+		eh.Handle(node.Name, &e)
+	}
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/samalba/dockerclient"
+)
+
+func TestEventFilterMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *eventFilter
+		event  dockerclient.Event
+		want   bool
+	}{
+		{"nil filter matches everything", nil, dockerclient.Event{Status: "start", Id: "abc", From: "busybox"}, true},
+		{
+			"event status matches",
+			&eventFilter{events: sliceToSet([]string{"start"})},
+			dockerclient.Event{Status: "start"},
+			true,
+		},
+		{
+			"event status mismatches",
+			&eventFilter{events: sliceToSet([]string{"start"})},
+			dockerclient.Event{Status: "die"},
+			false,
+		},
+		{
+			"container id matches",
+			&eventFilter{containers: sliceToSet([]string{"abc"})},
+			dockerclient.Event{Id: "abc"},
+			true,
+		},
+		{
+			"container id mismatches",
+			&eventFilter{containers: sliceToSet([]string{"abc"})},
+			dockerclient.Event{Id: "def"},
+			false,
+		},
+		{
+			"image matches",
+			&eventFilter{images: sliceToSet([]string{"busybox"})},
+			dockerclient.Event{From: "busybox"},
+			true,
+		},
+		{
+			"image mismatches",
+			&eventFilter{images: sliceToSet([]string{"busybox"})},
+			dockerclient.Event{From: "redis"},
+			false,
+		},
+		{
+			"all criteria must match",
+			&eventFilter{
+				events:     sliceToSet([]string{"start"}),
+				containers: sliceToSet([]string{"abc"}),
+			},
+			dockerclient.Event{Status: "start", Id: "def"},
+			false,
+		},
+		{"empty filter matches everything", &eventFilter{}, dockerclient.Event{Status: "start"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(&tt.event); got != tt.want {
+				t.Fatalf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventRingBufferAddEvictsOldest(t *testing.T) {
+	buf := newEventRingBuffer(2)
+
+	buf.Add(&Event{dockerclient.Event{Id: "1", Time: 1}, ""})
+	buf.Add(&Event{dockerclient.Event{Id: "2", Time: 2}, ""})
+	buf.Add(&Event{dockerclient.Event{Id: "3", Time: 3}, ""})
+
+	got := buf.Since(0, 0)
+	if len(got) != 2 {
+		t.Fatalf("expected buffer bounded to 2 events, got %d", len(got))
+	}
+	if got[0].Id != "2" || got[1].Id != "3" {
+		t.Fatalf("expected oldest event evicted, got %q, %q", got[0].Id, got[1].Id)
+	}
+}
+
+func TestEventRingBufferSince(t *testing.T) {
+	buf := newEventRingBuffer(10)
+	for _, tm := range []int64{1, 2, 3, 4, 5} {
+		buf.Add(&Event{dockerclient.Event{Id: "e", Time: tm}, ""})
+	}
+
+	tests := []struct {
+		name         string
+		since, until int64
+		wantTimes    []int64
+	}{
+		{"zero bounds are unbounded", 0, 0, []int64{1, 2, 3, 4, 5}},
+		{"since only", 3, 0, []int64{3, 4, 5}},
+		{"until only", 0, 3, []int64{1, 2, 3}},
+		{"since and until", 2, 4, []int64{2, 3, 4}},
+		{"empty window", 10, 20, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buf.Since(tt.since, tt.until)
+			if len(got) != len(tt.wantTimes) {
+				t.Fatalf("expected %d events, got %d", len(tt.wantTimes), len(got))
+			}
+			for i, e := range got {
+				if e.Time != tt.wantTimes[i] {
+					t.Fatalf("event %d: expected Time %d, got %d", i, tt.wantTimes[i], e.Time)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Package httputils holds small helpers shared by the api handlers.
+package httputils
+
+import (
+	"net/http"
+
+	"github.com/docker/swarm/api/errdefs"
+)
+
+type causer interface {
+	Cause() error
+}
+
+// FromError walks err's cause chain (as produced by github.com/pkg/errors)
+// and returns the HTTP status code matching the first errdefs interface it
+// implements. Errors that don't implement any of them map to 500.
+func FromError(err error) int {
+	if err == nil {
+		return http.StatusInternalServerError
+	}
+
+	for e := err; e != nil; {
+		switch e.(type) {
+		case errdefs.ErrNotFound:
+			return http.StatusNotFound
+		case errdefs.ErrConflict:
+			return http.StatusConflict
+		case errdefs.ErrInvalidParameter:
+			return http.StatusBadRequest
+		case errdefs.ErrUnavailable:
+			return http.StatusServiceUnavailable
+		case errdefs.ErrForbidden:
+			return http.StatusForbidden
+		case errdefs.ErrSystem:
+			return http.StatusInternalServerError
+		}
+
+		cause, ok := e.(causer)
+		if !ok {
+			break
+		}
+		e = cause.Cause()
+	}
+
+	return http.StatusInternalServerError
+}
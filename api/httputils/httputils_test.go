@@ -0,0 +1,38 @@
+package httputils
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/docker/swarm/api/errdefs"
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestFromError(t *testing.T) {
+	cause := errors.New("boom")
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, http.StatusInternalServerError},
+		{"plain", cause, http.StatusInternalServerError},
+		{"NotFound", errdefs.NotFound(cause), http.StatusNotFound},
+		{"Conflict", errdefs.Conflict(cause), http.StatusConflict},
+		{"InvalidParameter", errdefs.InvalidParameter(cause), http.StatusBadRequest},
+		{"Unavailable", errdefs.Unavailable(cause), http.StatusServiceUnavailable},
+		{"Forbidden", errdefs.Forbidden(cause), http.StatusForbidden},
+		{"System", errdefs.System(cause), http.StatusInternalServerError},
+		{"wrapped NotFound", pkgerrors.Wrap(errdefs.NotFound(cause), "context"), http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromError(tt.err); got != tt.want {
+				t.Fatalf("FromError(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// Package operations tracks long-running actions (container creates,
+// builds, image pulls, ...) so the API can hand callers a handle to poll or
+// cancel instead of blocking the request for the whole duration, modeled
+// after LXD's operations/response split.
+package operations
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Status is the lifecycle state of an Operation.
+type Status string
+
+const (
+	Pending Status = "pending"
+	Running Status = "running"
+	Success Status = "success"
+	Failure Status = "failure"
+)
+
+// Operation is a single tracked long-running action.
+type Operation struct {
+	mu        sync.Mutex
+	id        string
+	status    Status
+	resources []string
+	metadata  map[string]interface{}
+	err       error
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New creates a pending Operation tied to the given resource URLs (e.g.
+// "/containers/<id>").
+func New(resources ...string) *Operation {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Operation{
+		id:        newID(),
+		status:    Pending,
+		resources: resources,
+		metadata:  map[string]interface{}{},
+		ctx:       ctx,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+}
+
+// ID returns the Operation's unique identifier.
+func (op *Operation) ID() string {
+	return op.id
+}
+
+// Context is canceled when the Operation is canceled through Cancel. The
+// scheduler does not take this context, so canceling does not abort a
+// placement or pull that is already in progress.
+func (op *Operation) Context() context.Context {
+	return op.ctx
+}
+
+// SetRunning transitions a pending Operation to running.
+func (op *Operation) SetRunning() {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	op.status = Running
+}
+
+// SetProgress merges metadata into the Operation, e.g. pull progress.
+func (op *Operation) SetProgress(metadata map[string]interface{}) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	for k, v := range metadata {
+		op.metadata[k] = v
+	}
+}
+
+// Finish marks the Operation as done, successfully if err is nil.
+func (op *Operation) Finish(err error) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	if op.status == Success || op.status == Failure {
+		return
+	}
+	if err != nil {
+		op.status = Failure
+		op.err = err
+	} else {
+		op.status = Success
+	}
+	close(op.done)
+}
+
+// Cancel requests cancellation of the Operation's context. It is up to the
+// code driving the Operation to observe ctx.Done() and call Finish.
+func (op *Operation) Cancel() {
+	op.cancel()
+}
+
+// Wait blocks until the Operation finishes or timeout elapses (no timeout
+// waits forever) and returns the resulting status.
+func (op *Operation) Wait(timeout time.Duration) Status {
+	if timeout <= 0 {
+		<-op.done
+		return op.Status()
+	}
+
+	select {
+	case <-op.done:
+	case <-time.After(timeout):
+	}
+	return op.Status()
+}
+
+// Status returns the Operation's current status.
+func (op *Operation) Status() Status {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return op.status
+}
+
+// operationJSON is the wire representation of an Operation.
+type operationJSON struct {
+	Id        string                 `json:"Id"`
+	Status    Status                 `json:"Status"`
+	Resources []string               `json:"Resources"`
+	Metadata  map[string]interface{} `json:"Metadata"`
+	Err       string                 `json:"Err,omitempty"`
+}
+
+// MarshalJSON renders the Operation as a status document for the API.
+func (op *Operation) MarshalJSON() ([]byte, error) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+
+	doc := operationJSON{
+		Id:        op.id,
+		Status:    op.status,
+		Resources: op.resources,
+		Metadata:  op.metadata,
+	}
+	if op.err != nil {
+		doc.Err = op.err.Error()
+	}
+	return json.Marshal(doc)
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	// crypto/rand.Read never returns a short read without an error.
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
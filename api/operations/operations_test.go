@@ -0,0 +1,77 @@
+package operations
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOperationLifecycle(t *testing.T) {
+	op := New("/containers/create")
+
+	if op.Status() != Pending {
+		t.Fatalf("expected Pending, got %s", op.Status())
+	}
+
+	op.SetRunning()
+	if op.Status() != Running {
+		t.Fatalf("expected Running, got %s", op.Status())
+	}
+
+	op.Finish(nil)
+	if op.Status() != Success {
+		t.Fatalf("expected Success, got %s", op.Status())
+	}
+}
+
+func TestOperationFinishWithError(t *testing.T) {
+	op := New()
+	op.Finish(errors.New("boom"))
+
+	if op.Status() != Failure {
+		t.Fatalf("expected Failure, got %s", op.Status())
+	}
+}
+
+func TestOperationWaitTimeout(t *testing.T) {
+	op := New()
+
+	if status := op.Wait(10 * time.Millisecond); status != Pending {
+		t.Fatalf("expected Wait to time out as Pending, got %s", status)
+	}
+
+	op.Finish(nil)
+	if status := op.Wait(time.Second); status != Success {
+		t.Fatalf("expected Wait to observe Success, got %s", status)
+	}
+}
+
+func TestOperationCancel(t *testing.T) {
+	op := New()
+	op.Cancel()
+
+	select {
+	case <-op.Context().Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected context to be canceled")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	reg := NewRegistry()
+	op := New()
+	reg.Add(op)
+
+	if got, ok := reg.Get(op.ID()); !ok || got != op {
+		t.Fatalf("expected to find operation %s", op.ID())
+	}
+
+	if len(reg.List()) != 1 {
+		t.Fatalf("expected 1 operation, got %d", len(reg.List()))
+	}
+
+	reg.Delete(op.ID())
+	if _, ok := reg.Get(op.ID()); ok {
+		t.Fatal("expected operation to be removed")
+	}
+}
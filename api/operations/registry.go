@@ -0,0 +1,49 @@
+package operations
+
+import "sync"
+
+// Registry is an in-memory, concurrency-safe index of Operations keyed by
+// ID.
+type Registry struct {
+	mu  sync.RWMutex
+	ops map[string]*Operation
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{ops: make(map[string]*Operation)}
+}
+
+// Add registers op in the Registry.
+func (reg *Registry) Add(op *Operation) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.ops[op.ID()] = op
+}
+
+// Get returns the Operation for id, if any.
+func (reg *Registry) Get(id string) (*Operation, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	op, ok := reg.ops[id]
+	return op, ok
+}
+
+// Delete removes id from the Registry.
+func (reg *Registry) Delete(id string) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	delete(reg.ops, id)
+}
+
+// List returns every tracked Operation.
+func (reg *Registry) List() []*Operation {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	out := make([]*Operation, 0, len(reg.ops))
+	for _, op := range reg.ops {
+		out = append(out, op)
+	}
+	return out
+}
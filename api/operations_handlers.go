@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/docker/swarm/api/errdefs"
+	"github.com/docker/swarm/api/operations"
+	"github.com/gorilla/mux"
+)
+
+// GET /operations
+func getOperations(c *context, w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(c.operations.List())
+}
+
+// GET /operations/{id}
+func getOperation(c *context, w http.ResponseWriter, r *http.Request) {
+	op, err := lookupOperation(c, mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(op)
+}
+
+// DELETE /operations/{id}
+func deleteOperation(c *context, w http.ResponseWriter, r *http.Request) {
+	op, err := lookupOperation(c, mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	op.Cancel()
+	w.WriteHeader(http.StatusOK)
+}
+
+// GET /operations/{id}/wait?timeout=
+func waitOperation(c *context, w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, errdefs.InvalidParameter(err))
+		return
+	}
+
+	op, err := lookupOperation(c, mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var timeout time.Duration
+	if raw := r.Form.Get("timeout"); raw != "" {
+		secs, err := strconv.Atoi(raw)
+		if err != nil {
+			writeError(w, errdefs.InvalidParameter(err))
+			return
+		}
+		timeout = time.Duration(secs) * time.Second
+	}
+
+	op.Wait(timeout)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(op)
+}
+
+func lookupOperation(c *context, id string) (*operations.Operation, error) {
+	op, ok := c.operations.Get(id)
+	if !ok {
+		return nil, errdefs.NotFound(fmt.Errorf("Operation %s not found", id))
+	}
+	return op, nil
+}
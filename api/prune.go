@@ -0,0 +1,161 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/docker/swarm/api/errdefs"
+	"github.com/docker/swarm/cluster"
+	"github.com/docker/swarm/scheduler/filter"
+)
+
+// POST /containers/prune
+func postContainersPrune(c *context, w http.ResponseWriter, r *http.Request) {
+	prune(c, w, r, "/containers/prune", "ContainersDeleted")
+}
+
+// POST /images/prune
+func postImagesPrune(c *context, w http.ResponseWriter, r *http.Request) {
+	prune(c, w, r, "/images/prune", "ImagesDeleted")
+}
+
+// POST /volumes/prune
+func postVolumesPrune(c *context, w http.ResponseWriter, r *http.Request) {
+	prune(c, w, r, "/volumes/prune", "VolumesDeleted")
+}
+
+// prune fans the prune request identified by path out to every healthy
+// node in parallel, forwarding the filters query param verbatim, and merges
+// the per-node reports under deletedKey into a single document. Nodes that
+// fail are recorded as Warnings instead of failing the whole request, the
+// same tolerance getImagesJSON already applies to a degraded node.
+func prune(c *context, w http.ResponseWriter, r *http.Request, path, deletedKey string) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, errdefs.InvalidParameter(err))
+		return
+	}
+	filters := r.Form.Get("filters")
+
+	healthFilter := &filter.HealthFilter{}
+	healthy, err := healthFilter.Filter(nil, c.cluster.Nodes())
+	if err != nil {
+		writeError(w, errdefs.Unavailable(err))
+		return
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		nodes    = map[string]json.RawMessage{}
+		deleted  = []interface{}{}
+		space    uint64
+		warnings []string
+	)
+
+	for _, node := range healthy {
+		wg.Add(1)
+		go func(node *cluster.Node) {
+			defer wg.Done()
+
+			report, err := pruneNode(c, node, path, filters)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Errorf("Unable to prune %s: %v", node.Name, err)
+				warnings = append(warnings, fmt.Sprintf("%s: %v", node.Name, err))
+				return
+			}
+
+			nodes[node.Name] = report.raw
+			deleted = append(deleted, report.deleted...)
+			space += report.spaceReclaimed
+		}(node)
+	}
+	wg.Wait()
+
+	out := map[string]interface{}{
+		"Nodes":          nodes,
+		"SpaceReclaimed": space,
+	}
+	if len(warnings) > 0 {
+		out["Warnings"] = warnings
+	}
+	out[deletedKey] = deleted
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(out)
+}
+
+type nodePruneReport struct {
+	raw            json.RawMessage
+	deleted        []interface{}
+	spaceReclaimed uint64
+}
+
+// pruneNode forwards a single prune call to node and parses out the deleted
+// items and reclaimed space, regardless of which of the three prune
+// endpoints was hit.
+func pruneNode(c *context, node *cluster.Node, path, filters string) (*nodePruneReport, error) {
+	client, scheme := newClientAndScheme(c.tlsConfig)
+
+	nodeURL := scheme + "://" + node.Addr + path
+	if filters != "" {
+		nodeURL += "?filters=" + url.QueryEscape(filters)
+	}
+
+	resp, err := client.Post(nodeURL, "application/json", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := jsonRawBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var daemonErr struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(raw, &daemonErr); err == nil && daemonErr.Message != "" {
+			return nil, fmt.Errorf("%s (%d)", daemonErr.Message, resp.StatusCode)
+		}
+		return nil, fmt.Errorf("%s: %d", path, resp.StatusCode)
+	}
+
+	var parsed struct {
+		ContainersDeleted []interface{} `json:"ContainersDeleted"`
+		ImagesDeleted     []interface{} `json:"ImagesDeleted"`
+		VolumesDeleted    []interface{} `json:"VolumesDeleted"`
+		SpaceReclaimed    uint64        `json:"SpaceReclaimed"`
+	}
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return nil, err
+	}
+
+	var deleted []interface{}
+	switch {
+	case len(parsed.ContainersDeleted) > 0:
+		deleted = parsed.ContainersDeleted
+	case len(parsed.ImagesDeleted) > 0:
+		deleted = parsed.ImagesDeleted
+	case len(parsed.VolumesDeleted) > 0:
+		deleted = parsed.VolumesDeleted
+	}
+
+	return &nodePruneReport{raw: raw, deleted: deleted, spaceReclaimed: parsed.SpaceReclaimed}, nil
+}
+
+func jsonRawBody(resp *http.Response) (json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/docker/swarm/api/errdefs"
+	"github.com/docker/swarm/cluster"
+	"github.com/docker/swarm/scheduler/filter"
+	"github.com/gorilla/mux"
+	"github.com/samalba/dockerclient"
+)
+
+// GET /containers/{name:.*}/stats
+func getContainerStats(c *context, w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, errdefs.InvalidParameter(err))
+		return
+	}
+
+	name := mux.Vars(r)["name"]
+	container := c.cluster.Container(name)
+	if container == nil {
+		writeError(w, errdefs.NotFound(fmt.Errorf("Container %s not found", name)))
+		return
+	}
+
+	stream := r.Form.Get("stream") != "0"
+
+	client, scheme := newClientAndScheme(c.tlsConfig)
+
+	url := fmt.Sprintf("%s://%s/containers/%s/stats?stream=%s", scheme, container.Node.Addr, container.Id, strconv.FormatBool(stream))
+	resp, err := client.Get(url)
+	if err != nil {
+		writeError(w, errdefs.System(err))
+		return
+	}
+	defer resp.Body.Close()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, errdefs.System(fmt.Errorf("api does not support streaming responses")))
+		return
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	enc := json.NewEncoder(w)
+	for {
+		var stat map[string]interface{}
+		if err := dec.Decode(&stat); err != nil {
+			return
+		}
+		if err := enc.Encode(stat); err != nil {
+			return
+		}
+		flusher.Flush()
+		if !stream {
+			return
+		}
+	}
+}
+
+// nodeStats is a single container stats frame tagged with the node it came from.
+type nodeStats struct {
+	NodeName string      `json:"Node"`
+	NodeIP   string      `json:"NodeIP"`
+	Id       string      `json:"Id"`
+	Stats    interface{} `json:"Stats"`
+}
+
+// GET /cluster/stats
+func getClusterStats(c *context, w http.ResponseWriter, r *http.Request) {
+	healthFilter := &filter.HealthFilter{}
+	healthy, err := healthFilter.Filter(nil, c.cluster.Nodes())
+	if err != nil {
+		writeError(w, errdefs.Unavailable(err))
+		return
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = []*nodeStats{}
+	)
+
+	for _, node := range healthy {
+		wg.Add(1)
+		go func(node *cluster.Node) {
+			defer wg.Done()
+
+			stats, err := fetchNodeStats(c.tlsConfig, node)
+			if err != nil {
+				log.Errorf("Unable to fetch stats from %s: %v", node.Name, err)
+				return
+			}
+
+			mu.Lock()
+			results = append(results, stats...)
+			mu.Unlock()
+		}(node)
+	}
+	wg.Wait()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// fetchNodeStats lists the running containers on node and collects a single
+// stats sample for each of them.
+func fetchNodeStats(tlsConfig *tls.Config, node *cluster.Node) ([]*nodeStats, error) {
+	client, scheme := newClientAndScheme(tlsConfig)
+
+	resp, err := client.Get(scheme + "://" + node.Addr + "/containers/json?all=0")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var containers []*dockerclient.Container
+	if err := json.NewDecoder(resp.Body).Decode(&containers); err != nil {
+		return nil, err
+	}
+
+	out := make([]*nodeStats, 0, len(containers))
+	for _, container := range containers {
+		statsResp, err := client.Get(scheme + "://" + node.Addr + "/containers/" + container.Id + "/stats?stream=0")
+		if err != nil {
+			log.Errorf("Unable to fetch stats for %s on %s: %v", container.Id, node.Name, err)
+			continue
+		}
+
+		var stat interface{}
+		err = json.NewDecoder(statsResp.Body).Decode(&stat)
+		statsResp.Body.Close()
+		if err != nil {
+			log.Errorf("Unable to decode stats for %s on %s: %v", container.Id, node.Name, err)
+			continue
+		}
+
+		out = append(out, &nodeStats{
+			NodeName: node.Name,
+			NodeIP:   node.IP,
+			Id:       container.Id,
+			Stats:    stat,
+		})
+	}
+
+	return out, nil
+}